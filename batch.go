@@ -0,0 +1,151 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/awslabs/goformation/resources"
+	"github.com/sirupsen/logrus"
+)
+
+// batchEvent is a single named event payload to be invoked in batch mode,
+// either one file out of --event-dir or one entry from --events-manifest.
+type batchEvent struct {
+	Name string
+	Body string
+}
+
+// batchResult is the outcome of invoking the function once with a single
+// batchEvent, as recorded in the --report file.
+type batchResult struct {
+	Event      string `json:"event"`
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	DurationMs int64  `json:"duration_ms"`
+	ExitCode   int    `json:"exit_code"`
+	Error      string `json:"error,omitempty"`
+}
+
+// loadBatchEvents collects the events to invoke in batch mode, either every
+// file in eventDir (sorted by filename for reproducible runs) or every
+// entry of the events-manifest JSON file, which is a {name: event} map.
+func loadBatchEvents(eventDir string, manifestFile string) ([]batchEvent, error) {
+
+	if eventDir != "" {
+		files, err := ioutil.ReadDir(eventDir)
+		if err != nil {
+			return nil, fmt.Errorf("could not read event dir %s: %s", eventDir, err)
+		}
+
+		names := make([]string, 0, len(files))
+		for _, f := range files {
+			if !f.IsDir() {
+				names = append(names, f.Name())
+			}
+		}
+		sort.Strings(names)
+
+		events := make([]batchEvent, 0, len(names))
+		for _, name := range names {
+			data, err := ioutil.ReadFile(filepath.Join(eventDir, name))
+			if err != nil {
+				return nil, fmt.Errorf("could not read event file %s: %s", name, err)
+			}
+			events = append(events, batchEvent{Name: name, Body: string(data)})
+		}
+		return events, nil
+	}
+
+	manifest := map[string]json.RawMessage{}
+	data, err := ioutil.ReadFile(manifestFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read events manifest %s: %s", manifestFile, err)
+	}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("events manifest %s must be a JSON object of name to event: %s", manifestFile, err)
+	}
+
+	names := make([]string, 0, len(manifest))
+	for name := range manifest {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	events := make([]batchEvent, 0, len(names))
+	for _, name := range names {
+		events = append(events, batchEvent{Name: name, Body: string(manifest[name])})
+	}
+	return events, nil
+}
+
+// runBatch invokes function once per event, bounded to parallelism
+// concurrent invocations. Each invocation gets its own container via a
+// fresh call to NewRuntime, so one slow or crashing event can't affect the
+// others. Results are returned in the same order as events.
+func runBatch(function resources.AWSServerlessFunction, envVarsOverrides map[string]string, events []batchEvent, parallelism int, extensionShutdownDeadline time.Duration, log *logrus.Entry) []batchResult {
+
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	results := make([]batchResult, len(events))
+	sem := make(chan struct{}, parallelism)
+	var wg sync.WaitGroup
+
+	for i, event := range events {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(i int, event batchEvent) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			results[i] = invokeBatchEvent(function, envVarsOverrides, event, extensionShutdownDeadline, log)
+		}(i, event)
+	}
+
+	wg.Wait()
+	return results
+}
+
+// invokeBatchEvent runs a single event through its own container (and its
+// own Runtime API server) and captures the full result, never letting an
+// individual failure abort the rest of the batch.
+func invokeBatchEvent(function resources.AWSServerlessFunction, envVarsOverrides map[string]string, event batchEvent, extensionShutdownDeadline time.Duration, log *logrus.Entry) batchResult {
+
+	result := batchResult{Event: event.Name}
+	start := time.Now()
+
+	stdout, stderr, err := runSingleInvocation(function, envVarsOverrides, event.Body, extensionShutdownDeadline, log)
+	result.DurationMs = int64(time.Since(start) / time.Millisecond)
+	result.Stdout = stdout
+	result.Stderr = stderr
+
+	if err != nil {
+		result.Error = err.Error()
+		result.ExitCode = 1
+		log.WithField("event", event.Name).WithError(err).Error("Invocation failed")
+		return result
+	}
+
+	log.WithFields(logrus.Fields{
+		"event":       event.Name,
+		"duration_ms": result.DurationMs,
+	}).Info("Invocation complete")
+
+	return result
+}
+
+// writeReport marshals the batch results as JSON to the given path.
+func writeReport(path string, results []batchResult) error {
+	data, err := json.MarshalIndent(results, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not marshal report: %s", err)
+	}
+	return ioutil.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadBatchEventsFromEventDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "events-dir-*")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	if err := ioutil.WriteFile(filepath.Join(dir, "b.json"), []byte(`{"b":true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "a.json"), []byte(`{"a":true}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := loadBatchEvents(dir, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(events) != 2 || events[0].Name != "a.json" || events[1].Name != "b.json" {
+		t.Fatalf("expected events sorted by filename [a.json, b.json], got %+v", events)
+	}
+}
+
+func TestLoadBatchEventsFromManifest(t *testing.T) {
+	manifest := writeTempTemplate(t, `{"first": {"x": 1}, "second": {"y": 2}}`)
+
+	events, err := loadBatchEvents("", manifest)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(events) != 2 || events[0].Name != "first" || events[1].Name != "second" {
+		t.Fatalf("expected events sorted by name [first, second], got %+v", events)
+	}
+}
+
+func TestWriteReportMarshalsResults(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report-*")
+	if err != nil {
+		t.Fatalf("could not create temp dir: %s", err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+
+	path := filepath.Join(dir, "report.json")
+	results := []batchResult{{Event: "a", ExitCode: 0}, {Event: "b", Error: "boom", ExitCode: 1}}
+
+	if err := writeReport(path, results); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("could not read report: %s", err)
+	}
+
+	var got []batchResult
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("report is not valid JSON: %s", err)
+	}
+	if len(got) != 2 || got[1].Error != "boom" {
+		t.Fatalf("expected the written report to round-trip the results, got %+v", got)
+	}
+}
@@ -0,0 +1,32 @@
+package events
+
+import "encoding/json"
+
+func init() {
+	Register("apigateway", apiGatewayBuilder{})
+}
+
+type apiGatewayBuilder struct{}
+
+type apiGatewayProxyRequest struct {
+	HTTPMethod string            `json:"httpMethod"`
+	Path       string            `json:"path"`
+	Body       string            `json:"body"`
+	Headers    map[string]string `json:"headers"`
+}
+
+// Build synthesizes an API Gateway proxy integration request. Recognised
+// params are "method", "path" and "body".
+func (apiGatewayBuilder) Build(params map[string]string) (string, error) {
+	e := apiGatewayProxyRequest{
+		HTTPMethod: param(params, "method", "GET"),
+		Path:       param(params, "path", "/"),
+		Body:       param(params, "body", ""),
+		Headers: map[string]string{
+			"Accept": "*/*",
+		},
+	}
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	return string(b), err
+}
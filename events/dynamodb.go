@@ -0,0 +1,78 @@
+package events
+
+import "encoding/json"
+
+func init() {
+	Register("dynamodb", dynamoDBBuilder{})
+}
+
+type dynamoDBBuilder struct{}
+
+// DynamoDBEvent mirrors github.com/aws/aws-lambda-go/events.DynamoDBEvent.
+// It is exported so that the `sam local poll` DynamoDB Streams source can
+// build the same shape from real stream records rather than a single
+// synthetic sample.
+type DynamoDBEvent struct {
+	Records []DynamoDBEventRecord `json:"Records"`
+}
+
+// DynamoDBEventRecord is a single stream record within a DynamoDBEvent.
+type DynamoDBEventRecord struct {
+	EventID        string               `json:"eventID"`
+	EventName      string               `json:"eventName"`
+	EventSource    string               `json:"eventSource"`
+	EventSourceARN string               `json:"eventSourceARN"`
+	AwsRegion      string               `json:"awsRegion"`
+	Dynamodb       DynamoDBStreamRecord `json:"dynamodb"`
+}
+
+// DynamoDBStreamRecord is the stream-specific payload of a
+// DynamoDBEventRecord.
+type DynamoDBStreamRecord struct {
+	Keys           map[string]DynamoDBAttribute `json:"Keys"`
+	NewImage       map[string]DynamoDBAttribute `json:"NewImage"`
+	StreamViewType string                       `json:"StreamViewType"`
+}
+
+// DynamoDBAttribute is a single attribute value. Only the String ("S")
+// type is represented, matching what `sam local poll` needs to replay a
+// stream record's key and string attributes.
+type DynamoDBAttribute struct {
+	S string `json:"S,omitempty"`
+}
+
+// Build synthesizes a DynamoDB Streams INSERT event. Recognised params are
+// "table-name", "key" and "region".
+func (dynamoDBBuilder) Build(params map[string]string) (string, error) {
+	tableName := param(params, "table-name", "example-table")
+	key := param(params, "key", "example-id")
+	region := param(params, "region", "us-east-1")
+
+	streamArn := "arn:aws:dynamodb:" + region + ":123456789012:table/" + tableName + "/stream/1970-01-01T00:00:00.000"
+	image := map[string]DynamoDBAttribute{"Id": {S: key}}
+
+	return DynamoDBStreamEvent(streamArn, region, []string{"INSERT"}, []map[string]DynamoDBAttribute{image})
+}
+
+// DynamoDBStreamEvent builds the Lambda event payload for a batch of real
+// DynamoDB Streams records, as received by `sam local poll`.
+func DynamoDBStreamEvent(streamArn string, region string, eventNames []string, images []map[string]DynamoDBAttribute) (string, error) {
+	e := DynamoDBEvent{Records: make([]DynamoDBEventRecord, len(images))}
+	for i, image := range images {
+		e.Records[i] = DynamoDBEventRecord{
+			EventID:        "00000000000000000000000000000000",
+			EventName:      eventNames[i],
+			EventSource:    "aws:dynamodb",
+			EventSourceARN: streamArn,
+			AwsRegion:      region,
+			Dynamodb: DynamoDBStreamRecord{
+				Keys:           image,
+				NewImage:       image,
+				StreamViewType: "NEW_AND_OLD_IMAGES",
+			},
+		}
+	}
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	return string(b), err
+}
@@ -0,0 +1,59 @@
+// Package events synthesizes sample Lambda event payloads for the AWS event
+// sources that commonly trigger functions, so that `sam local invoke` can be
+// exercised without hand-crafting a JSON payload. The shapes mirror the
+// structs exposed by github.com/aws/aws-lambda-go/events.
+package events
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Builder generates a sample event payload from a set of named parameters.
+// Implementations should apply sensible defaults for any parameter the
+// caller does not supply.
+type Builder interface {
+	// Build renders the event payload as JSON, using params to fill in the
+	// fields that are meaningful for this event source.
+	Build(params map[string]string) (string, error)
+}
+
+// registry maps an --event-type name to the Builder that knows how to
+// synthesize it.
+var registry = map[string]Builder{}
+
+// Register adds a Builder under the given event-type name. It is called
+// from each builder's init() so that adding a new event source only
+// requires dropping a new file into this package.
+func Register(name string, b Builder) {
+	registry[name] = b
+}
+
+// Types returns the sorted list of registered event-type names, for use in
+// help text and validation errors.
+func Types() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Generate builds the sample event payload for the named event type,
+// passing through the supplied parameters.
+func Generate(eventType string, params map[string]string) (string, error) {
+	b, ok := registry[eventType]
+	if !ok {
+		return "", fmt.Errorf("unknown event type '%s' (supported: %v)", eventType, Types())
+	}
+	return b.Build(params)
+}
+
+// param returns params[name], falling back to def if it is unset or empty.
+func param(params map[string]string, name, def string) string {
+	if v, ok := params[name]; ok && v != "" {
+		return v
+	}
+	return def
+}
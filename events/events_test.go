@@ -0,0 +1,105 @@
+package events
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestGenerateUnknownEventType(t *testing.T) {
+	if _, err := Generate("no-such-type", nil); err == nil {
+		t.Fatal("expected an error for an unregistered event type")
+	}
+}
+
+func TestTypesIncludesBuiltinBuilders(t *testing.T) {
+	types := Types()
+	for _, want := range []string{"sqs", "sns", "s3", "kinesis", "dynamodb", "apigateway", "schedule"} {
+		found := false
+		for _, got := range types {
+			if got == want {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected Types() to include %q, got %v", want, types)
+		}
+	}
+}
+
+func TestParamFallsBackToDefaultWhenUnsetOrEmpty(t *testing.T) {
+	if got := param(map[string]string{}, "key", "default"); got != "default" {
+		t.Errorf("param() with an unset key = %q, want default", got)
+	}
+	if got := param(map[string]string{"key": ""}, "key", "default"); got != "default" {
+		t.Errorf("param() with an empty value = %q, want default", got)
+	}
+	if got := param(map[string]string{"key": "value"}, "key", "default"); got != "value" {
+		t.Errorf("param() with a set value = %q, want value", got)
+	}
+}
+
+func TestSQSMessageEventRoundTrips(t *testing.T) {
+	payload, err := Generate("sqs", map[string]string{"body": "hello"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var e SQSEvent
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		t.Fatalf("generated SQS event is not valid JSON: %s", err)
+	}
+	if len(e.Records) != 1 || e.Records[0].Body != "hello" {
+		t.Fatalf("expected one record with body 'hello', got %+v", e.Records)
+	}
+}
+
+func TestKinesisDataEventBase64EncodesData(t *testing.T) {
+	payload, err := KinesisDataEvent("arn", "us-east-1", []string{"pk"}, [][]byte{[]byte("raw data")}, []string{"1"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if strings.Contains(payload, "raw data") {
+		t.Fatal("expected Kinesis record data to be base64-encoded, found the raw bytes in the payload")
+	}
+
+	var e KinesisEvent
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		t.Fatalf("generated Kinesis event is not valid JSON: %s", err)
+	}
+	if e.Records[0].Kinesis.SequenceNumber != "1" {
+		t.Fatalf("expected sequence number '1', got %q", e.Records[0].Kinesis.SequenceNumber)
+	}
+}
+
+func TestDynamoDBStreamEventBuildsRecordFromImage(t *testing.T) {
+	payload, err := DynamoDBStreamEvent("arn", "us-east-1", []string{"INSERT"}, []map[string]DynamoDBAttribute{
+		{"Id": {S: "abc"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var e DynamoDBEvent
+	if err := json.Unmarshal([]byte(payload), &e); err != nil {
+		t.Fatalf("generated DynamoDB event is not valid JSON: %s", err)
+	}
+	if e.Records[0].EventName != "INSERT" || e.Records[0].Dynamodb.NewImage["Id"].S != "abc" {
+		t.Fatalf("unexpected record shape: %+v", e.Records[0])
+	}
+}
+
+func TestAPIGatewayBuilderDefaults(t *testing.T) {
+	payload, err := Generate("apigateway", map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var req apiGatewayProxyRequest
+	if err := json.Unmarshal([]byte(payload), &req); err != nil {
+		t.Fatalf("generated API Gateway event is not valid JSON: %s", err)
+	}
+	if req.HTTPMethod != "GET" || req.Path != "/" {
+		t.Fatalf("expected GET / by default, got %s %s", req.HTTPMethod, req.Path)
+	}
+}
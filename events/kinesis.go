@@ -0,0 +1,68 @@
+package events
+
+import (
+	"encoding/base64"
+	"encoding/json"
+)
+
+func init() {
+	Register("kinesis", kinesisBuilder{})
+}
+
+type kinesisBuilder struct{}
+
+// KinesisEvent mirrors github.com/aws/aws-lambda-go/events.KinesisEvent. It
+// is exported so that the `sam local poll` Kinesis source can build the
+// same shape from real records rather than a single synthetic sample.
+type KinesisEvent struct {
+	Records []KinesisEventRecord `json:"Records"`
+}
+
+// KinesisEventRecord is a single record within a KinesisEvent.
+type KinesisEventRecord struct {
+	EventSource    string        `json:"eventSource"`
+	EventSourceARN string        `json:"eventSourceARN"`
+	AwsRegion      string        `json:"awsRegion"`
+	Kinesis        KinesisRecord `json:"kinesis"`
+}
+
+// KinesisRecord is the Kinesis-specific payload of a KinesisEventRecord.
+type KinesisRecord struct {
+	PartitionKey   string `json:"partitionKey"`
+	Data           string `json:"data"`
+	SequenceNumber string `json:"sequenceNumber"`
+}
+
+// Build synthesizes a Kinesis data stream event. Recognised params are
+// "stream-name", "data", "partition-key" and "region"; "data" is base64-encoded as
+// the real event source does.
+func (kinesisBuilder) Build(params map[string]string) (string, error) {
+	streamName := param(params, "stream-name", "example-stream")
+	data := param(params, "data", "example data")
+	partitionKey := param(params, "partition-key", "partitionKey-0")
+	region := param(params, "region", "us-east-1")
+
+	streamArn := "arn:aws:kinesis:" + region + ":123456789012:stream/" + streamName
+	return KinesisDataEvent(streamArn, region, []string{partitionKey}, [][]byte{[]byte(data)}, []string{"00000000000000000000000000000000000000000000000000000000000000"})
+}
+
+// KinesisDataEvent builds the Lambda event payload for a batch of real
+// Kinesis records, as received by `sam local poll`.
+func KinesisDataEvent(streamArn string, region string, partitionKeys []string, data [][]byte, sequenceNumbers []string) (string, error) {
+	e := KinesisEvent{Records: make([]KinesisEventRecord, len(data))}
+	for i, d := range data {
+		e.Records[i] = KinesisEventRecord{
+			EventSource:    "aws:kinesis",
+			EventSourceARN: streamArn,
+			AwsRegion:      region,
+			Kinesis: KinesisRecord{
+				PartitionKey:   partitionKeys[i],
+				Data:           base64.StdEncoding.EncodeToString(d),
+				SequenceNumber: sequenceNumbers[i],
+			},
+		}
+	}
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	return string(b), err
+}
@@ -0,0 +1,70 @@
+package events
+
+import "encoding/json"
+
+func init() {
+	Register("s3", s3Builder{})
+}
+
+type s3Builder struct{}
+
+type s3Event struct {
+	Records []s3EventRecord `json:"Records"`
+}
+
+type s3EventRecord struct {
+	EventVersion string      `json:"eventVersion"`
+	EventSource  string      `json:"eventSource"`
+	AwsRegion    string      `json:"awsRegion"`
+	EventName    string      `json:"eventName"`
+	S3           s3EventData `json:"s3"`
+}
+
+type s3EventData struct {
+	SchemaVersion string        `json:"s3SchemaVersion"`
+	Bucket        s3EventBucket `json:"bucket"`
+	Object        s3EventObject `json:"object"`
+}
+
+type s3EventBucket struct {
+	Name string `json:"name"`
+	Arn  string `json:"arn"`
+}
+
+type s3EventObject struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+}
+
+// Build synthesizes an S3 ObjectCreated:Put event. Recognised params are
+// "bucket", "key" and "region".
+func (s3Builder) Build(params map[string]string) (string, error) {
+	bucket := param(params, "bucket", "example-bucket")
+	key := param(params, "key", "test/key")
+	region := param(params, "region", "us-east-1")
+
+	e := s3Event{
+		Records: []s3EventRecord{
+			{
+				EventVersion: "2.1",
+				EventSource:  "aws:s3",
+				AwsRegion:    region,
+				EventName:    "ObjectCreated:Put",
+				S3: s3EventData{
+					SchemaVersion: "1.0",
+					Bucket: s3EventBucket{
+						Name: bucket,
+						Arn:  "arn:aws:s3:::" + bucket,
+					},
+					Object: s3EventObject{
+						Key:  key,
+						Size: 1024,
+					},
+				},
+			},
+		},
+	}
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	return string(b), err
+}
@@ -0,0 +1,43 @@
+package events
+
+import "encoding/json"
+
+func init() {
+	Register("schedule", scheduleBuilder{})
+}
+
+type scheduleBuilder struct{}
+
+// scheduledEvent mirrors the CloudWatch Events "Scheduled Event" shape
+// delivered to functions triggered by a Schedule expression.
+type scheduledEvent struct {
+	Version    string   `json:"version"`
+	ID         string   `json:"id"`
+	DetailType string   `json:"detail-type"`
+	Source     string   `json:"source"`
+	AccountID  string   `json:"account"`
+	Time       string   `json:"time"`
+	Region     string   `json:"region"`
+	Resources  []string `json:"resources"`
+	Detail     struct{} `json:"detail"`
+}
+
+// Build synthesizes a CloudWatch Events scheduled event. Recognised params
+// are "region".
+func (scheduleBuilder) Build(params map[string]string) (string, error) {
+	region := param(params, "region", "us-east-1")
+
+	e := scheduledEvent{
+		Version:    "0",
+		ID:         "00000000-0000-0000-0000-000000000000",
+		DetailType: "Scheduled Event",
+		Source:     "aws.events",
+		AccountID:  "123456789012",
+		Time:       "1970-01-01T00:00:00Z",
+		Region:     region,
+		Resources:  []string{"arn:aws:events:" + region + ":123456789012:rule/example-rule"},
+	}
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	return string(b), err
+}
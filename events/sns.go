@@ -0,0 +1,54 @@
+package events
+
+import "encoding/json"
+
+func init() {
+	Register("sns", snsBuilder{})
+}
+
+type snsBuilder struct{}
+
+type snsEvent struct {
+	Records []snsEventRecord `json:"Records"`
+}
+
+type snsEventRecord struct {
+	EventVersion string         `json:"EventVersion"`
+	EventSource  string         `json:"EventSource"`
+	Sns          snsEntityEvent `json:"Sns"`
+}
+
+type snsEntityEvent struct {
+	TopicArn  string `json:"TopicArn"`
+	Message   string `json:"Message"`
+	Subject   string `json:"Subject"`
+	Timestamp string `json:"Timestamp"`
+	MessageId string `json:"MessageId"`
+}
+
+// Build synthesizes an SNS Notification event. Recognised params are
+// "topic-arn", "message" and "subject".
+func (snsBuilder) Build(params map[string]string) (string, error) {
+	topicArn := param(params, "topic-arn", "arn:aws:sns:us-east-1:123456789012:example-topic")
+	message := param(params, "message", "example message")
+	subject := param(params, "subject", "example subject")
+
+	e := snsEvent{
+		Records: []snsEventRecord{
+			{
+				EventVersion: "1.0",
+				EventSource:  "aws:sns",
+				Sns: snsEntityEvent{
+					TopicArn:  topicArn,
+					Message:   message,
+					Subject:   subject,
+					Timestamp: "1970-01-01T00:00:00.000Z",
+					MessageId: "00000000-0000-0000-0000-000000000000",
+				},
+			},
+		},
+	}
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	return string(b), err
+}
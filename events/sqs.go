@@ -0,0 +1,53 @@
+package events
+
+import "encoding/json"
+
+func init() {
+	Register("sqs", sqsBuilder{})
+}
+
+type sqsBuilder struct{}
+
+// SQSEvent mirrors github.com/aws/aws-lambda-go/events.SQSEvent. It is
+// exported so that the `sam local poll` SQS source can build the same
+// shape from real messages rather than a single synthetic sample.
+type SQSEvent struct {
+	Records []SQSEventRecord `json:"Records"`
+}
+
+// SQSEventRecord is a single message within an SQSEvent.
+type SQSEventRecord struct {
+	MessageId      string `json:"messageId"`
+	Body           string `json:"body"`
+	EventSource    string `json:"eventSource"`
+	EventSourceARN string `json:"eventSourceARN"`
+	AwsRegion      string `json:"awsRegion"`
+}
+
+// Build synthesizes an SQS message event. Recognised params are
+// "queue-arn", "body" and "region".
+func (sqsBuilder) Build(params map[string]string) (string, error) {
+	queueArn := param(params, "queue-arn", "arn:aws:sqs:us-east-1:123456789012:example-queue")
+	body := param(params, "body", "example message body")
+	region := param(params, "region", "us-east-1")
+
+	return SQSMessageEvent(queueArn, region, []string{"00000000-0000-0000-0000-000000000000"}, []string{body})
+}
+
+// SQSMessageEvent builds the Lambda event payload for a batch of real SQS
+// messages, as received by `sam local poll`.
+func SQSMessageEvent(queueArn string, region string, messageIds []string, bodies []string) (string, error) {
+	e := SQSEvent{Records: make([]SQSEventRecord, len(bodies))}
+	for i, body := range bodies {
+		e.Records[i] = SQSEventRecord{
+			MessageId:      messageIds[i],
+			Body:           body,
+			EventSource:    "aws:sqs",
+			EventSourceARN: queueArn,
+			AwsRegion:      region,
+		}
+	}
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	return string(b), err
+}
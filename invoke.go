@@ -2,19 +2,21 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
+	"time"
 
 	"io/ioutil"
 
 	"io"
-	"sync"
 
 	"encoding/json"
 
 	"github.com/awslabs/goformation"
 	"github.com/awslabs/goformation/resources"
 	"github.com/codegangsta/cli"
+	"github.com/sirupsen/logrus"
+
+	"github.com/awslabs/aws-sam-local/events"
 )
 
 func invoke(c *cli.Context) {
@@ -22,33 +24,52 @@ func invoke(c *cli.Context) {
 	// Setup the logger
 	stdout := io.Writer(os.Stdout)
 	stderr := io.Writer(os.Stderr)
+	logOut := io.Writer(os.Stderr)
 	logarg := c.String("log")
 
 	if len(logarg) > 0 {
 		if logFile, err := os.Create(logarg); err == nil {
 			stderr = io.Writer(logFile)
 			stdout = io.Writer(logFile)
-			log.SetOutput(stderr)
+			logOut = io.Writer(logFile)
 		} else {
-			log.Fatalf("Failed to open log file %s: %s\n", c.String("log"), err)
+			fmt.Fprintf(os.Stderr, "ERROR: Failed to open log file %s: %s\n", c.String("log"), err)
+			os.Exit(1)
 		}
 	}
 
+	logger := newLogger(logOut, c.String("log-level"), c.String("log-format"))
+
 	name := c.Args().First()
 	if name == "" {
 		fmt.Fprintf(os.Stderr, "ERROR: You must provide a function identifier (function's Logical ID in the SAM template) as the first argument.\n")
 		os.Exit(1)
 	}
 
-	template, _, errs := goformation.Open(c.String("template"))
+	paramOverrides, err := resolveParameterOverrides(c.String("var-file"), c.StringSlice("var"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	if err := validateParameterOverrides(c.String("template"), paramOverrides); err != nil {
+		logger.Fatal(err)
+	}
+
+	resolvedTemplate, cleanupResolvedTemplate, err := applyParameterOverrides(c.String("template"), paramOverrides)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	defer cleanupResolvedTemplate()
+
+	template, _, errs := goformation.Open(resolvedTemplate)
 	if len(errs) > 0 {
 		for _, err := range errs {
-			log.Printf("%s\n", err)
+			logger.Error(err)
 		}
 		os.Exit(1)
 	}
 
-	log.Printf("Successfully parsed %s (version %s)\n", c.String("template"), template.Version())
+	logger.WithField("template", c.String("template")).WithField("version", template.Version()).Info("Successfully parsed SAM template")
 
 	// Find the specified function in the SAM template
 	var function resources.AWSServerlessFunction
@@ -62,18 +83,19 @@ func invoke(c *cli.Context) {
 	}
 
 	if function == nil {
-		log.Fatalf("Could not find a AWS::Serverless::Function with logical ID '%s'\n", name)
+		logger.WithField("function", name).Fatal("Could not find a AWS::Serverless::Function with this logical ID")
 	}
 
+	log := logger.WithField("function", name).WithField("runtime", function.Runtime())
+
 	// Check connectivity to docker
 	dockerVersion, err := getDockerVersion()
 	if err != nil {
-		log.Printf("Running AWS SAM projects locally requires Docker. Have you got it installed?\n")
-		log.Printf("%s\n", err)
-		os.Exit(1)
+		log.Warn("Running AWS SAM projects locally requires Docker. Have you got it installed?")
+		log.Fatal(err)
 	}
 
-	log.Printf("Connected to Docker %s", dockerVersion)
+	log.WithField("docker_version", dockerVersion).Debug("Connected to Docker")
 
 	// FIXME: Move all the argument parsing into a shared file - invoke and start commands have duplicate code
 	envVarsFile := c.String("env-vars")
@@ -82,17 +104,17 @@ func invoke(c *cli.Context) {
 
 		f, err := os.Open(c.String("env-vars"))
 		if err != nil {
-			log.Fatalf("Failed to open environment variables values file\n%s\n", err)
+			log.Fatalf("Failed to open environment variables values file: %s", err)
 		}
 
 		data, err := ioutil.ReadAll(f)
 		if err != nil {
-			log.Fatalf("Unable to read the environment variable values file\n%s\n", err)
+			log.Fatalf("Unable to read the environment variable values file: %s", err)
 		}
 
 		// This is a JSON of structure {FunctionName: {key:value}, FunctionName: {key:value}}
 		if err = json.Unmarshal(data, &envVarsOverrides); err != nil {
-			log.Fatalf("Environment variable values must be a valid JSON\n%s\n", err)
+			log.Fatalf("Environment variable values must be a valid JSON: %s", err)
 		}
 
 	}
@@ -103,48 +125,91 @@ func invoke(c *cli.Context) {
 		funcEnvVarsOverrides = map[string]string{}
 	}
 
-	runt, err := NewRuntime(function, funcEnvVarsOverrides)
-	if err != nil {
-		log.Fatalf("Could not initiate %s runtime: %s\n", function.Runtime(), err)
+	extensionShutdownDeadline := time.Duration(c.Int("extensions-timeout")) * time.Millisecond
+
+	eventDir := c.String("event-dir")
+	eventsManifest := c.String("events-manifest")
+	if eventDir != "" || eventsManifest != "" {
+		events, err := loadBatchEvents(eventDir, eventsManifest)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		log.WithField("events", len(events)).WithField("parallel", c.Int("parallel")).Info("Running batch invocation")
+		results := runBatch(function, funcEnvVarsOverrides, events, c.Int("parallel"), extensionShutdownDeadline, log)
+
+		if reportFile := c.String("report"); reportFile != "" {
+			if err := writeReport(reportFile, results); err != nil {
+				log.Fatal(err)
+			}
+		}
+
+		return
 	}
 
 	eventFile := c.String("event")
+	eventType := c.String("event-type")
 	event := ""
-	if eventFile == "" {
+
+	switch {
+	case eventType != "":
+		// The user asked us to synthesize a sample event instead of
+		// supplying one, e.g. --event-type s3 --bucket foo --key bar.jpg
+		params := map[string]string{
+			"bucket":        c.String("bucket"),
+			"key":           c.String("key"),
+			"topic-arn":     c.String("topic-arn"),
+			"queue-arn":     c.String("queue-arn"),
+			"message":       c.String("message"),
+			"subject":       c.String("subject"),
+			"method":        c.String("method"),
+			"path":          c.String("path"),
+			"body":          c.String("body"),
+			"table-name":    c.String("table-name"),
+			"stream-name":   c.String("stream-name"),
+			"data":          c.String("data"),
+			"partition-key": c.String("partition-key"),
+			"region":        c.String("region"),
+		}
+
+		generated, err := events.Generate(eventType, params)
+		if err != nil {
+			log.Fatalf("Could not generate %s event: %s", eventType, err)
+		}
+		event = generated
+
+	case eventFile == "":
 		// The event payload wasn't provided with --event, so read from stdin
-		log.Printf("Reading invoke payload from stdin (you can also pass it from file with --event)\n")
+		log.Info("Reading invoke payload from stdin (you can also pass it from file with --event)")
 		pb, err := ioutil.ReadAll(os.Stdin)
 		if err != nil {
-			log.Fatalf("Could not read event from stdin: %s\n", err)
+			log.Fatalf("Could not read event from stdin: %s", err)
 		}
 		event = string(pb)
-	} else {
+
+	default:
 		// The event payload should be read from the file provided
 		pb, err := ioutil.ReadFile(eventFile)
 		if err != nil {
-			log.Fatalf("Could not read event from file: %s\n", err)
+			log.Fatalf("Could not read event from file: %s", err)
 		}
 		event = string(pb)
 	}
 
-	stdoutTxt, stderrTxt, err := runt.Invoke(event)
+	log = log.WithField("event_bytes", len(event))
+	start := time.Now()
+
+	stdoutTxt, stderrTxt, err := runSingleInvocation(function, funcEnvVarsOverrides, event, extensionShutdownDeadline, log)
 	if err != nil {
-		log.Fatalf("Could not invoke function: %s\n", err)
+		log.WithField("duration_ms", int64(time.Since(start)/time.Millisecond)).WithField("exit_code", 1).Fatalf("Could not invoke function: %s", err)
 	}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
-
-	go func() {
-		io.Copy(stderr, stderrTxt)
-		wg.Done()
-	}()
-
-	go func() {
-		io.Copy(stdout, stdoutTxt)
-		wg.Done()
-	}()
+	io.WriteString(stderr, stderrTxt)
+	io.WriteString(stdout, stdoutTxt)
 
-	wg.Wait()
+	log.WithFields(logrus.Fields{
+		"duration_ms": int64(time.Since(start) / time.Millisecond),
+		"exit_code":   0,
+	}).Info("Invocation complete")
 
 }
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// newLogger builds the structured logger shared by the invoke and start
+// commands. level is one of "debug", "info", "warn" or "error" (defaulting
+// to "info" on an unrecognised value); format is "text" or "json".
+func newLogger(out io.Writer, level string, format string) *logrus.Logger {
+
+	logger := logrus.New()
+	logger.Out = out
+
+	if format == "json" {
+		logger.Formatter = &logrus.JSONFormatter{}
+	} else {
+		logger.Formatter = &logrus.TextFormatter{}
+	}
+
+	parsedLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		parsedLevel = logrus.InfoLevel
+	}
+	logger.Level = parsedLevel
+
+	return logger
+}
@@ -0,0 +1,212 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/codegangsta/cli"
+)
+
+// Version is set at build time via -ldflags
+var Version = "dev"
+
+func main() {
+
+	app := cli.NewApp()
+	app.Name = "sam"
+	app.Usage = "AWS SAM Local - Develop and test your Lambda functions and SAM templates locally"
+	app.Version = Version
+
+	app.Commands = []cli.Command{
+		{
+			Name:      "invoke",
+			Usage:     "Invoke a Lambda function locally",
+			ArgsUsage: "FUNCTION_IDENTIFIER",
+			Action:    invoke,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "template, t",
+					Value: "template.yaml",
+					Usage: "Path to a SAM template",
+				},
+				cli.StringFlag{
+					Name:  "event, e",
+					Usage: "Path to a file containing the Lambda event payload (defaults to reading from stdin)",
+				},
+				cli.StringFlag{
+					Name:  "event-type",
+					Usage: "Generate a sample event payload for a given event source instead of supplying --event (s3, sns, sqs, apigateway, dynamodb, kinesis, schedule)",
+				},
+				cli.StringFlag{
+					Name:  "bucket",
+					Usage: "S3 bucket name (--event-type s3)",
+				},
+				cli.StringFlag{
+					Name:  "key",
+					Usage: "S3 object key or DynamoDB item Id (--event-type s3, dynamodb)",
+				},
+				cli.StringFlag{
+					Name:  "topic-arn",
+					Usage: "SNS topic ARN (--event-type sns)",
+				},
+				cli.StringFlag{
+					Name:  "queue-arn",
+					Usage: "SQS queue ARN (--event-type sqs)",
+				},
+				cli.StringFlag{
+					Name:  "message",
+					Usage: "SNS message body (--event-type sns)",
+				},
+				cli.StringFlag{
+					Name:  "subject",
+					Usage: "SNS subject (--event-type sns)",
+				},
+				cli.StringFlag{
+					Name:  "method",
+					Usage: "HTTP method (--event-type apigateway)",
+				},
+				cli.StringFlag{
+					Name:  "path",
+					Usage: "HTTP path (--event-type apigateway)",
+				},
+				cli.StringFlag{
+					Name:  "body",
+					Usage: "HTTP or SQS message body (--event-type apigateway, sqs)",
+				},
+				cli.StringFlag{
+					Name:  "table-name",
+					Usage: "DynamoDB table name (--event-type dynamodb)",
+				},
+				cli.StringFlag{
+					Name:  "stream-name",
+					Usage: "Kinesis stream name (--event-type kinesis)",
+				},
+				cli.StringFlag{
+					Name:  "data",
+					Usage: "Kinesis record data (--event-type kinesis)",
+				},
+				cli.StringFlag{
+					Name:  "partition-key",
+					Usage: "Kinesis partition key (--event-type kinesis)",
+				},
+				cli.StringFlag{
+					Name:  "region",
+					Usage: "AWS region to use in the generated event (defaults to us-east-1)",
+				},
+				cli.StringFlag{
+					Name:  "event-dir",
+					Usage: "Invoke the function once per file in this directory, running in batch mode",
+				},
+				cli.StringFlag{
+					Name:  "events-manifest",
+					Usage: "Invoke the function once per entry of this JSON {name: event} manifest, running in batch mode",
+				},
+				cli.IntFlag{
+					Name:  "parallel",
+					Value: 1,
+					Usage: "Number of concurrent invocations to run in batch mode (--event-dir / --events-manifest)",
+				},
+				cli.StringFlag{
+					Name:  "report",
+					Usage: "Path to write a JSON report of batch invocation results to",
+				},
+				cli.IntFlag{
+					Name:  "extensions-timeout",
+					Value: 500,
+					Usage: "Milliseconds to let registered Lambda Extensions react to SHUTDOWN before the container is killed",
+				},
+				cli.StringFlag{
+					Name:  "env-vars",
+					Usage: "Path to a JSON file containing function environment variable overrides",
+				},
+				cli.StringSliceFlag{
+					Name:  "var",
+					Usage: "Override a CloudFormation template parameter value (name=value), can be specified multiple times",
+				},
+				cli.StringFlag{
+					Name:  "var-file",
+					Usage: "Path to a YAML or JSON file of CloudFormation template parameter overrides",
+				},
+				cli.StringFlag{
+					Name:  "log",
+					Usage: "Path to a file to send runtime logs to",
+				},
+				cli.StringFlag{
+					Name:  "log-level",
+					Value: "info",
+					Usage: "Log level to emit: debug, info, warn or error",
+				},
+				cli.StringFlag{
+					Name:  "log-format",
+					Value: "text",
+					Usage: "Log output format: text or json",
+				},
+			},
+		},
+		{
+			Name:      "poll",
+			Usage:     "Continuously poll a function's SQS, Kinesis or DynamoDB Streams event sources and invoke it locally",
+			ArgsUsage: "FUNCTION_IDENTIFIER",
+			Action:    poll,
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "template, t",
+					Value: "template.yaml",
+					Usage: "Path to a SAM template",
+				},
+				cli.StringSliceFlag{
+					Name:  "var",
+					Usage: "Override a CloudFormation template parameter value (name=value), can be specified multiple times",
+				},
+				cli.StringFlag{
+					Name:  "var-file",
+					Usage: "Path to a YAML or JSON file of CloudFormation template parameter overrides",
+				},
+				cli.StringFlag{
+					Name:  "endpoint-url",
+					Usage: "Override the AWS service endpoint, e.g. http://localhost:4566 to poll LocalStack/ElasticMQ instead of real AWS",
+				},
+				cli.StringFlag{
+					Name:  "region",
+					Value: "us-east-1",
+					Usage: "AWS region the queue/stream lives in",
+				},
+				cli.IntFlag{
+					Name:  "batch-size",
+					Value: 10,
+					Usage: "Maximum number of records to receive per poll, passed through to the event source",
+				},
+				cli.IntFlag{
+					Name:  "visibility-timeout",
+					Value: 30,
+					Usage: "Seconds an SQS message is hidden from other consumers while its invocation runs",
+				},
+				cli.IntFlag{
+					Name:  "retry-interval",
+					Value: 2,
+					Usage: "Seconds to wait between Kinesis/DynamoDB Streams poll cycles that returned no records",
+				},
+				cli.IntFlag{
+					Name:  "extensions-timeout",
+					Value: 500,
+					Usage: "Milliseconds to let registered Lambda Extensions react to SHUTDOWN before the container is killed",
+				},
+				cli.StringFlag{
+					Name:  "log-level",
+					Value: "info",
+					Usage: "Log level to emit: debug, info, warn or error",
+				},
+				cli.StringFlag{
+					Name:  "log-format",
+					Value: "text",
+					Usage: "Log output format: text or json",
+				},
+			},
+		},
+	}
+
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// templateParameters captures just enough of a SAM/CloudFormation
+// template's top-level Parameters section to check which parameters have a
+// Default - the value (if any) is left as interface{} since a Default can
+// be a string, number or bool in the template itself.
+type templateParameters struct {
+	Parameters map[string]map[string]interface{} `yaml:"Parameters"`
+}
+
+// validateParameterOverrides fails loudly, listing every offending
+// parameter, if the template declares a Parameter with no Default that
+// overrides doesn't supply a value for - rather than letting intrinsics
+// like Ref fail later with a confusing resolution error deep inside
+// goformation. Templates that fail to parse here are left for goformation's
+// own Open to report, since full template validation isn't this function's
+// job.
+func validateParameterOverrides(templatePath string, overrides map[string]string) error {
+
+	data, err := ioutil.ReadFile(templatePath)
+	if err != nil {
+		return fmt.Errorf("could not read template %s: %s", templatePath, err)
+	}
+
+	var parsed templateParameters
+	if err := yaml.Unmarshal(data, &parsed); err != nil {
+		return nil
+	}
+
+	var missing []string
+	for name, def := range parsed.Parameters {
+		if _, ok := overrides[name]; ok {
+			continue
+		}
+		if _, ok := def["Default"]; ok {
+			continue
+		}
+		missing = append(missing, name)
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	sort.Strings(missing)
+	return fmt.Errorf("missing required parameter(s) with no Default in the template: %s (supply with --var name=value or --var-file)", strings.Join(missing, ", "))
+}
+
+// applyParameterOverrides makes --var/--var-file overrides actually affect
+// how the template resolves: since the template is loaded standalone, with
+// no CloudFormation stack to hold separate "supplied parameter values",
+// Ref/Fn::Sub/Fn::FindInMap can only resolve a parameter from its Default.
+// This rewrites the Default of every declared parameter that has an
+// override to the overridden value and writes the result to a temp file,
+// so goformation.Open resolves intrinsics against what the user passed in
+// rather than the template's own defaults.
+//
+// If there are no overrides, or the template doesn't parse as a generic
+// YAML document, templatePath is returned unchanged and cleanup is a
+// no-op - malformed templates are left for goformation.Open to report.
+func applyParameterOverrides(templatePath string, overrides map[string]string) (resolvedPath string, cleanup func(), err error) {
+	noop := func() {}
+
+	if len(overrides) == 0 {
+		return templatePath, noop, nil
+	}
+
+	data, err := ioutil.ReadFile(templatePath)
+	if err != nil {
+		return "", noop, fmt.Errorf("could not read template %s: %s", templatePath, err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return templatePath, noop, nil
+	}
+
+	params, ok := doc["Parameters"].(map[interface{}]interface{})
+	if !ok {
+		return templatePath, noop, nil
+	}
+
+	changed := false
+	for name, value := range overrides {
+		def, ok := params[name].(map[interface{}]interface{})
+		if !ok {
+			continue
+		}
+		def["Default"] = value
+		changed = true
+	}
+
+	if !changed {
+		return templatePath, noop, nil
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return "", noop, fmt.Errorf("could not re-encode template with parameter overrides: %s", err)
+	}
+
+	tmp, err := ioutil.TempFile("", "sam-local-template-*.yaml")
+	if err != nil {
+		return "", noop, fmt.Errorf("could not create temporary template for parameter overrides: %s", err)
+	}
+	defer tmp.Close()
+
+	if _, err := tmp.Write(out); err != nil {
+		os.Remove(tmp.Name())
+		return "", noop, fmt.Errorf("could not write temporary template for parameter overrides: %s", err)
+	}
+
+	return tmp.Name(), func() { os.Remove(tmp.Name()) }, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+func writeTempTemplate(t *testing.T, contents string) string {
+	t.Helper()
+	f, err := ioutil.TempFile("", "template-*.yaml")
+	if err != nil {
+		t.Fatalf("could not create temp template: %s", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(contents); err != nil {
+		t.Fatalf("could not write temp template: %s", err)
+	}
+	t.Cleanup(func() { os.Remove(f.Name()) })
+	return f.Name()
+}
+
+func TestValidateParameterOverrides(t *testing.T) {
+	template := writeTempTemplate(t, `
+Parameters:
+  HasDefault:
+    Type: String
+    Default: foo
+  NoDefault:
+    Type: String
+`)
+
+	if err := validateParameterOverrides(template, map[string]string{}); err == nil {
+		t.Fatal("expected an error for a missing required parameter with no Default")
+	}
+
+	if err := validateParameterOverrides(template, map[string]string{"NoDefault": "bar"}); err != nil {
+		t.Fatalf("unexpected error once the missing parameter is overridden: %s", err)
+	}
+}
+
+func TestApplyParameterOverridesRewritesDefault(t *testing.T) {
+	template := writeTempTemplate(t, `
+Parameters:
+  Env:
+    Type: String
+    Default: dev
+`)
+
+	resolved, cleanup, err := applyParameterOverrides(template, map[string]string{"Env": "prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer cleanup()
+
+	if resolved == template {
+		t.Fatal("expected a rewritten temp template, got the original path back")
+	}
+
+	data, err := ioutil.ReadFile(resolved)
+	if err != nil {
+		t.Fatalf("could not read resolved template: %s", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("resolved template is not valid YAML: %s", err)
+	}
+	params := doc["Parameters"].(map[interface{}]interface{})
+	env := params["Env"].(map[interface{}]interface{})
+	if env["Default"] != "prod" {
+		t.Fatalf("expected Env's Default to be overridden to 'prod', got %v", env["Default"])
+	}
+}
+
+func TestApplyParameterOverridesNoopWithoutOverrides(t *testing.T) {
+	template := writeTempTemplate(t, "Parameters:\n  Env:\n    Type: String\n    Default: dev\n")
+
+	resolved, cleanup, err := applyParameterOverrides(template, map[string]string{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer cleanup()
+
+	if resolved != template {
+		t.Fatalf("expected the original template path back when there are no overrides, got %s", resolved)
+	}
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/awslabs/goformation"
+	"github.com/awslabs/goformation/resources"
+	"github.com/codegangsta/cli"
+	"github.com/sirupsen/logrus"
+
+	eventsources "github.com/awslabs/aws-sam-local/poll"
+)
+
+// pollableEventSourceTypes are the SAM Events "Type" values that map to a
+// pull-based source poll can read from. Api, SNS and Schedule are push- or
+// time-based rather than something a poller reads from, so they aren't
+// listed here - poll skips such an event source with a warning, before it
+// ever reaches eventsources.New, rather than silently ignoring it.
+var pollableEventSourceTypes = []string{"SQS", "Kinesis", "DynamoDB"}
+
+// poll implements `sam local poll`: it reads a function's Events section
+// from the SAM template and, for every pull-based event source found
+// (SQS/Kinesis/DynamoDB Streams), continuously polls it and invokes the
+// function through the same NewRuntime plumbing invoke uses, one container
+// per received batch.
+func poll(c *cli.Context) {
+
+	logger := newLogger(os.Stderr, c.String("log-level"), c.String("log-format"))
+
+	name := c.Args().First()
+	if name == "" {
+		fmt.Fprintf(os.Stderr, "ERROR: You must provide a function identifier (function's Logical ID in the SAM template) as the first argument.\n")
+		os.Exit(1)
+	}
+
+	paramOverrides, err := resolveParameterOverrides(c.String("var-file"), c.StringSlice("var"))
+	if err != nil {
+		logger.Fatal(err)
+	}
+
+	if err := validateParameterOverrides(c.String("template"), paramOverrides); err != nil {
+		logger.Fatal(err)
+	}
+
+	resolvedTemplate, cleanupResolvedTemplate, err := applyParameterOverrides(c.String("template"), paramOverrides)
+	if err != nil {
+		logger.Fatal(err)
+	}
+	defer cleanupResolvedTemplate()
+
+	template, _, errs := goformation.Open(resolvedTemplate)
+	if len(errs) > 0 {
+		for _, err := range errs {
+			logger.Error(err)
+		}
+		os.Exit(1)
+	}
+
+	var function resources.AWSServerlessFunction
+	functions := template.GetResourcesByType("AWS::Serverless::Function")
+	for resourceName, resource := range functions {
+		if resourceName == name {
+			if f, ok := resource.(resources.AWSServerlessFunction); ok {
+				function = f
+			}
+		}
+	}
+
+	if function == nil {
+		logger.WithField("function", name).Fatal("Could not find a AWS::Serverless::Function with this logical ID")
+	}
+
+	log := logger.WithField("function", name).WithField("runtime", function.Runtime())
+
+	if _, err := getDockerVersion(); err != nil {
+		log.Fatal("Running AWS SAM projects locally requires Docker. Have you got it installed?")
+	}
+
+	eventSourceMappings := function.Events()
+	if len(eventSourceMappings) == 0 {
+		log.Fatal("This function has no Events in the SAM template to poll")
+	}
+
+	cfg := eventsources.Config{
+		EndpointURL:       c.String("endpoint-url"),
+		Region:            c.String("region"),
+		BatchSize:         c.Int("batch-size"),
+		VisibilityTimeout: time.Duration(c.Int("visibility-timeout")) * time.Second,
+		RetryInterval:     time.Duration(c.Int("retry-interval")) * time.Second,
+	}
+
+	extensionShutdownDeadline := time.Duration(c.Int("extensions-timeout")) * time.Millisecond
+	envVarsOverrides := map[string]string{}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		log.Info("Stopping poll...")
+		cancel()
+	}()
+
+	started := 0
+	for sourceName, mapping := range eventSourceMappings {
+		sourceLog := log.WithField("event_source", sourceName).WithField("type", mapping.Type)
+
+		if !isPollable(mapping.Type) {
+			sourceLog.Warn("Skipping event source - not a pull-based source that can be polled locally")
+			continue
+		}
+
+		arn, err := arnFromEventSourceMapping(mapping)
+		if err != nil {
+			sourceLog.WithError(err).Error("Could not resolve an ARN to poll for this event source")
+			continue
+		}
+
+		sourceCfg := cfg
+		sourceCfg.ARN = arn
+		if batchSize, ok := batchSizeFromMapping(mapping); ok {
+			sourceCfg.BatchSize = batchSize
+		}
+
+		source, err := eventsources.New(mapping.Type, sourceCfg)
+		if err != nil {
+			sourceLog.WithError(err).Error("Could not start polling this event source")
+			continue
+		}
+
+		started++
+		go func(sourceLog *logrus.Entry, source eventsources.Source) {
+			err := source.Run(ctx, func(event string) error {
+				_, _, err := runSingleInvocation(function, envVarsOverrides, event, extensionShutdownDeadline, sourceLog)
+				if err != nil {
+					sourceLog.WithError(err).Error("Invocation failed")
+				}
+				return err
+			})
+			if err != nil && ctx.Err() == nil {
+				sourceLog.WithError(err).Error("Polling stopped unexpectedly")
+			}
+		}(sourceLog, source)
+	}
+
+	if started == 0 {
+		log.Fatal("No pollable event sources were found for this function")
+	}
+
+	<-ctx.Done()
+}
+
+func isPollable(samEventType string) bool {
+	for _, t := range pollableEventSourceTypes {
+		if t == samEventType {
+			return true
+		}
+	}
+	return false
+}
+
+// batchSizeFromMapping reads a per-source BatchSize override from the SAM
+// event mapping's own Properties, so a template's BatchSize wins over the
+// process-wide --batch-size flag applied to every other event source. YAML/
+// JSON unmarshal numeric properties as float64, so that's the only numeric
+// type handled here.
+func batchSizeFromMapping(mapping resources.AWSServerlessFunctionEventSource) (int, bool) {
+	v, ok := mapping.Properties["BatchSize"]
+	if !ok {
+		return 0, false
+	}
+	n, ok := v.(float64)
+	if !ok {
+		return 0, false
+	}
+	return int(n), true
+}
+
+// arnFromEventSourceMapping pulls the queue/stream ARN out of a SAM event
+// source's Properties, where SAM's resource types name the field Queue or
+// Stream depending on the event source Type.
+func arnFromEventSourceMapping(mapping resources.AWSServerlessFunctionEventSource) (string, error) {
+	for _, key := range []string{"Queue", "Stream"} {
+		if v, ok := mapping.Properties[key]; ok {
+			if arn, ok := v.(string); ok && arn != "" {
+				return arn, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no Queue or Stream ARN found in event source properties")
+}
@@ -0,0 +1,130 @@
+package poll
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+
+	"github.com/awslabs/aws-sam-local/events"
+)
+
+func init() {
+	Register("DynamoDB", newDynamoDBStreamSource)
+}
+
+type dynamoDBStreamSource struct {
+	client    *dynamodbstreams.DynamoDBStreams
+	streamArn string
+	cfg       Config
+}
+
+func newDynamoDBStreamSource(cfg Config) (Source, error) {
+	sess, err := newSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dynamoDBStreamSource{
+		client:    dynamodbstreams.New(sess),
+		streamArn: cfg.ARN,
+		cfg:       cfg,
+	}, nil
+}
+
+func (s *dynamoDBStreamSource) Run(ctx context.Context, handle func(event string) error) error {
+	desc, err := s.client.DescribeStreamWithContext(ctx, &dynamodbstreams.DescribeStreamInput{StreamArn: aws.String(s.streamArn)})
+	if err != nil {
+		return fmt.Errorf("could not describe stream %s: %s", s.streamArn, err)
+	}
+
+	shardIterators := map[string]*string{}
+	for _, shard := range desc.StreamDescription.Shards {
+		iter, err := s.client.GetShardIteratorWithContext(ctx, &dynamodbstreams.GetShardIteratorInput{
+			StreamArn:         aws.String(s.streamArn),
+			ShardId:           shard.ShardId,
+			ShardIteratorType: aws.String(dynamodbstreams.ShardIteratorTypeLatest),
+		})
+		if err != nil {
+			return fmt.Errorf("could not get shard iterator for %s/%s: %s", s.streamArn, aws.StringValue(shard.ShardId), err)
+		}
+		shardIterators[aws.StringValue(shard.ShardId)] = iter.ShardIterator
+	}
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		gotAny := false
+
+		for shardID, iterator := range shardIterators {
+			if iterator == nil {
+				continue
+			}
+
+			records, err := s.client.GetRecordsWithContext(ctx, &dynamodbstreams.GetRecordsInput{
+				ShardIterator: iterator,
+				Limit:         aws.Int64(int64(s.cfg.BatchSize)),
+			})
+			if err != nil {
+				return fmt.Errorf("could not get records from shard %s: %s", shardID, err)
+			}
+
+			if len(records.Records) == 0 {
+				// Always advance past an empty read - there's nothing to
+				// retry, and AWS recommends refreshing the iterator on
+				// every call rather than holding onto one that may expire.
+				shardIterators[shardID] = records.NextShardIterator
+				continue
+			}
+			gotAny = true
+
+			eventNames := make([]string, len(records.Records))
+			images := make([]map[string]events.DynamoDBAttribute, len(records.Records))
+			for i, r := range records.Records {
+				eventNames[i] = aws.StringValue(r.EventName)
+				images[i] = toDynamoDBAttributes(r.Dynamodb.NewImage)
+			}
+
+			event, err := events.DynamoDBStreamEvent(s.streamArn, s.cfg.Region, eventNames, images)
+			if err != nil {
+				return err
+			}
+
+			if err := handle(event); err != nil {
+				// Don't advance the iterator past a batch the handler
+				// failed on - leaving it where it was means the next
+				// cycle re-reads and retries the same records, mirroring
+				// the SQS source's in-flight-message redrive behaviour.
+				continue
+			}
+
+			shardIterators[shardID] = records.NextShardIterator
+		}
+
+		// Only back off when a full pass over every shard came back empty -
+		// RetryInterval is documented as the wait between cycles that
+		// returned no records, not a throttle on an actively producing
+		// stream.
+		if !gotAny {
+			time.Sleep(s.cfg.RetryInterval)
+		}
+	}
+}
+
+// toDynamoDBAttributes keeps only the String ("S") typed attributes from a
+// stream record's image, which covers the common case of string keys
+// without pulling in the full DynamoDB attribute-value model. Numeric ("N")
+// and every other attribute type are dropped.
+func toDynamoDBAttributes(image map[string]*dynamodbstreams.AttributeValue) map[string]events.DynamoDBAttribute {
+	out := make(map[string]events.DynamoDBAttribute, len(image))
+	for name, attr := range image {
+		if attr.S != nil {
+			out[name] = events.DynamoDBAttribute{S: aws.StringValue(attr.S)}
+		}
+	}
+	return out
+}
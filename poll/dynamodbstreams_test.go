@@ -0,0 +1,27 @@
+package poll
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodbstreams"
+)
+
+func TestToDynamoDBAttributesKeepsOnlyStringValues(t *testing.T) {
+	image := map[string]*dynamodbstreams.AttributeValue{
+		"name":  {S: aws.String("widget")},
+		"count": {N: aws.String("3")},
+	}
+
+	attrs := toDynamoDBAttributes(image)
+
+	if len(attrs) != 1 {
+		t.Fatalf("expected only the string-typed attribute to survive, got %d entries", len(attrs))
+	}
+	if attrs["name"].S != "widget" {
+		t.Fatalf("expected name=widget, got %q", attrs["name"].S)
+	}
+	if _, ok := attrs["count"]; ok {
+		t.Fatal("expected the non-string 'count' attribute to be dropped")
+	}
+}
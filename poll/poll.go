@@ -0,0 +1,84 @@
+// Package poll continuously pulls messages from the event sources that can
+// back a SAM function's Events mapping (SQS, Kinesis, DynamoDB Streams) and
+// hands each batch to the caller as a ready-to-invoke Lambda event, so that
+// `sam local poll` can exercise a handler's behavior under the same
+// long-running, polling model the real service uses.
+package poll
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+)
+
+// Config describes one event source mapping to poll: which stream/queue to
+// read from, where to read it from (real AWS, or a LocalStack/ElasticMQ
+// endpoint), and the batching behaviour to apply.
+type Config struct {
+	// ARN is the queue/stream/topic ARN taken from the SAM template's
+	// Events section.
+	ARN string
+
+	// EndpointURL overrides the AWS service endpoint, e.g.
+	// http://localhost:4566 for LocalStack.
+	EndpointURL string
+
+	Region            string
+	BatchSize         int
+	VisibilityTimeout time.Duration
+	RetryInterval     time.Duration
+}
+
+// Source polls a single event source, invoking handle once per batch with
+// the synthesized Lambda event payload. Run blocks until ctx is cancelled
+// or the underlying client returns an error; an error from handle does not
+// stop Run, the failed batch is left to be read again.
+type Source interface {
+	Run(ctx context.Context, handle func(event string) error) error
+}
+
+// Factory builds a Source for one SAM event source Type (e.g. "SQS").
+type Factory func(cfg Config) (Source, error)
+
+var registry = map[string]Factory{}
+
+// Register adds a Factory under the SAM Events "Type" it handles. Called
+// from each source file's init().
+func Register(samEventType string, f Factory) {
+	registry[samEventType] = f
+}
+
+// New builds the Source registered for samEventType, or an error listing
+// the supported types if there is none - this is also how "SNS" is
+// rejected, since SNS delivers by push (HTTP/SQS subscription), not by
+// polling a queue or stream.
+func New(samEventType string, cfg Config) (Source, error) {
+	f, ok := registry[samEventType]
+	if !ok {
+		supported := make([]string, 0, len(registry))
+		for t := range registry {
+			supported = append(supported, t)
+		}
+		return nil, fmt.Errorf("event source type '%s' cannot be polled locally (supported: %v)", samEventType, supported)
+	}
+	return f(cfg)
+}
+
+// newSession builds an AWS SDK session honoring Config.Region and, when
+// set, Config.EndpointURL - the hook that lets `sam local poll` talk to
+// LocalStack/ElasticMQ instead of real AWS.
+func newSession(cfg Config) (*session.Session, error) {
+	awsCfg := aws.NewConfig().WithRegion(cfg.Region)
+
+	if cfg.EndpointURL != "" {
+		awsCfg = awsCfg.
+			WithEndpoint(cfg.EndpointURL).
+			WithDisableSSL(strings.HasPrefix(cfg.EndpointURL, "http://"))
+	}
+
+	return session.NewSession(awsCfg)
+}
@@ -0,0 +1,10 @@
+package poll
+
+import "testing"
+
+func TestNewUnsupportedSourceType(t *testing.T) {
+	_, err := New("SNS", Config{})
+	if err == nil {
+		t.Fatal("expected an error for a source type with no registered Factory (e.g. SNS, which is push- not pull-based)")
+	}
+}
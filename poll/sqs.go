@@ -0,0 +1,153 @@
+package poll
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+
+	"github.com/awslabs/aws-sam-local/events"
+)
+
+func init() {
+	Register("SQS", newSQSSource)
+}
+
+// ReceiveMessage's MaxNumberOfMessages is hard-limited by the SQS API to
+// 1-10 regardless of what a template's BatchSize asks for - a larger
+// configured batch size is honored by issuing more receives per poll cycle
+// (see receiveBatch), not by sending an out-of-range value AWS will reject
+// outright.
+const (
+	sqsMinReceiveBatchSize = 1
+	sqsMaxReceiveBatchSize = 10
+)
+
+func clampReceiveBatchSize(n int) int64 {
+	if n < sqsMinReceiveBatchSize {
+		n = sqsMinReceiveBatchSize
+	}
+	if n > sqsMaxReceiveBatchSize {
+		n = sqsMaxReceiveBatchSize
+	}
+	return int64(n)
+}
+
+// receiveWaitTimeSeconds long-polls on the first call of a receiveBatch
+// loop, so an idle queue doesn't busy-poll, but returns immediately on any
+// top-up call - once a cycle has something to deliver, it's better to
+// invoke with a partial batch than to keep blocking for up to 20s per call
+// trying to fill it completely.
+func receiveWaitTimeSeconds(alreadyReceived int) int64 {
+	if alreadyReceived == 0 {
+		return 20
+	}
+	return 0
+}
+
+type sqsSource struct {
+	client   *sqs.SQS
+	queueURL string
+	queueArn string
+	cfg      Config
+}
+
+func newSQSSource(cfg Config) (Source, error) {
+	sess, err := newSession(cfg)
+	if err != nil {
+		return nil, err
+	}
+	client := sqs.New(sess)
+
+	queueURL, err := sqsQueueURL(client, cfg.ARN)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sqsSource{client: client, queueURL: queueURL, queueArn: cfg.ARN, cfg: cfg}, nil
+}
+
+// sqsQueueURL resolves a queue ARN (as written in a SAM template) to the
+// queue URL the SQS API expects, by asking for the queue by name.
+func sqsQueueURL(client *sqs.SQS, arn string) (string, error) {
+	name := arn
+	if idx := strings.LastIndex(arn, ":"); idx != -1 {
+		name = arn[idx+1:]
+	}
+
+	out, err := client.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(name)})
+	if err != nil {
+		return "", fmt.Errorf("could not resolve queue URL for %s: %s", arn, err)
+	}
+	return aws.StringValue(out.QueueUrl), nil
+}
+
+func (s *sqsSource) Run(ctx context.Context, handle func(event string) error) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		ids, bodies, receipts, err := s.receiveBatch(ctx)
+		if err != nil {
+			return err
+		}
+
+		if len(ids) == 0 {
+			continue
+		}
+
+		event, err := events.SQSMessageEvent(s.queueArn, s.cfg.Region, ids, bodies)
+		if err != nil {
+			return err
+		}
+
+		if err := handle(event); err != nil {
+			// Leave the messages in flight - they become visible again
+			// after VisibilityTimeout and get retried, matching the real
+			// service's redrive behaviour rather than silently dropping
+			// a failed batch.
+			continue
+		}
+
+		for _, receipt := range receipts {
+			s.client.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
+				QueueUrl:      aws.String(s.queueURL),
+				ReceiptHandle: receipt,
+			})
+		}
+	}
+}
+
+// receiveBatch issues as many ReceiveMessage calls as it takes to
+// accumulate up to cfg.BatchSize messages, since a single ReceiveMessage is
+// capped at 10 by the API - a template's BatchSize: 50 is honored by
+// looping, not by a single out-of-range request. It stops early, with
+// whatever it has, the moment a call comes back empty.
+func (s *sqsSource) receiveBatch(ctx context.Context) (ids []string, bodies []string, receipts []*string, err error) {
+	for len(ids) < s.cfg.BatchSize {
+		out, err := s.client.ReceiveMessageWithContext(ctx, &sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(s.queueURL),
+			MaxNumberOfMessages: aws.Int64(clampReceiveBatchSize(s.cfg.BatchSize - len(ids))),
+			VisibilityTimeout:   aws.Int64(int64(s.cfg.VisibilityTimeout.Seconds())),
+			WaitTimeSeconds:     aws.Int64(receiveWaitTimeSeconds(len(ids))),
+		})
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("could not receive messages from %s: %s", s.queueArn, err)
+		}
+
+		if len(out.Messages) == 0 {
+			break
+		}
+
+		for _, m := range out.Messages {
+			ids = append(ids, aws.StringValue(m.MessageId))
+			bodies = append(bodies, aws.StringValue(m.Body))
+			receipts = append(receipts, m.ReceiptHandle)
+		}
+	}
+
+	return ids, bodies, receipts, nil
+}
@@ -0,0 +1,28 @@
+package poll
+
+import "testing"
+
+func TestClampReceiveBatchSize(t *testing.T) {
+	cases := map[int]int64{
+		0:  1,
+		1:  1,
+		10: 10,
+		50: 10,
+		-5: 1,
+	}
+
+	for in, want := range cases {
+		if got := clampReceiveBatchSize(in); got != want {
+			t.Errorf("clampReceiveBatchSize(%d) = %d, want %d", in, got, want)
+		}
+	}
+}
+
+func TestReceiveWaitTimeSeconds(t *testing.T) {
+	if got := receiveWaitTimeSeconds(0); got != 20 {
+		t.Errorf("receiveWaitTimeSeconds(0) = %d, want 20 (long poll on the first receive)", got)
+	}
+	if got := receiveWaitTimeSeconds(3); got != 0 {
+		t.Errorf("receiveWaitTimeSeconds(3) = %d, want 0 (don't block topping up a partial batch)", got)
+	}
+}
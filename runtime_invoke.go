@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/awslabs/goformation/resources"
+	"github.com/sirupsen/logrus"
+
+	"github.com/awslabs/aws-sam-local/runtimeapi"
+)
+
+// isCustomRuntime reports whether runtime is one of the provided/
+// provided.al2 runtimes, which speak the Runtime API over HTTP instead of
+// reading the event from stdin.
+func isCustomRuntime(runtime string) bool {
+	return strings.HasPrefix(runtime, "provided")
+}
+
+// runSingleInvocation runs function once against event in its own
+// container, with its own Runtime API server, and returns the captured
+// stdout/stderr. It is the common path shared by invoke, batch invocation
+// (invokeBatchEvent) and `sam local poll`, all of which invoke a function
+// many times over its lifetime and need one container per call.
+//
+// For provided/provided.al2 runtimes, the container never reads stdin -
+// it polls the Runtime API server for its next invocation and posts the
+// result back over HTTP, so the event is delivered via server.Invoke and
+// that response (not runt.Invoke's stdout capture) is the function result.
+func runSingleInvocation(function resources.AWSServerlessFunction, envVarsOverrides map[string]string, event string, extensionShutdownDeadline time.Duration, log *logrus.Entry) (stdout string, stderr string, err error) {
+
+	workerEnvVarsOverrides := make(map[string]string, len(envVarsOverrides)+1)
+	for k, v := range envVarsOverrides {
+		workerEnvVarsOverrides[k] = v
+	}
+
+	server, shutdownRuntimeAPI := startRuntimeAPI(workerEnvVarsOverrides, extensionShutdownDeadline, log)
+	defer shutdownRuntimeAPI()
+
+	runt, err := NewRuntime(function, workerEnvVarsOverrides)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Custom runtimes fetch their event over HTTP, so hand it to the
+	// server concurrently with starting the container rather than waiting
+	// for runt.Invoke to return first - the container won't produce any
+	// stdout of its own until the Runtime API server answers this.
+	var apiResultCh chan runtimeapi.Result
+	if isCustomRuntime(function.Runtime()) {
+		apiResultCh = make(chan runtimeapi.Result, 1)
+		go func() {
+			apiResultCh <- server.Invoke(event)
+		}()
+	}
+
+	stdoutTxt, stderrTxt, err := runt.Invoke(event)
+	if err != nil {
+		return "", "", err
+	}
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	var wg sync.WaitGroup
+	wg.Add(2)
+
+	go func() {
+		stdoutBuf.ReadFrom(stdoutTxt)
+		wg.Done()
+	}()
+	go func() {
+		stderrBuf.ReadFrom(stderrTxt)
+		wg.Done()
+	}()
+	wg.Wait()
+
+	if apiResultCh != nil {
+		result := <-apiResultCh
+		if result.Error != nil {
+			return stdoutBuf.String(), stderrBuf.String(), result.Error
+		}
+		return result.Payload, stderrBuf.String(), nil
+	}
+
+	return stdoutBuf.String(), stderrBuf.String(), nil
+}
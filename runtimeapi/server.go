@@ -0,0 +1,244 @@
+// Package runtimeapi implements an in-process emulation of the Lambda
+// Runtime API and Extensions API, so that `provided`/`provided.al2`
+// functions and the github.com/aws/aws-lambda-go/lambda client library work
+// unmodified against a locally-run container. A Server is started per
+// invocation of `sam local invoke`, and its address is injected into the
+// container as AWS_LAMBDA_RUNTIME_API.
+package runtimeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Result is what the runtime reported back for an invocation, via either
+// the /response or /error Runtime API endpoint.
+type Result struct {
+	Payload string
+	Error   error
+}
+
+type pendingInvocation struct {
+	id      string
+	payload string
+}
+
+type extension struct {
+	id     string
+	events chan string
+}
+
+// Server emulates the subset of the Runtime API and Extensions API that
+// the `provided` runtimes and custom extensions rely on: handing out the
+// next invocation event, accepting the function's response or error, and
+// fanning SHUTDOWN out to registered extensions.
+type Server struct {
+	listener  net.Listener
+	closed    chan struct{}
+	closeOnce sync.Once
+
+	mu         sync.Mutex
+	next       chan pendingInvocation
+	pending    map[string]chan Result
+	extensions []*extension
+}
+
+// NewServer starts listening on an ephemeral localhost port. Call Serve to
+// start handling requests, and Addr to discover the host:port to inject as
+// AWS_LAMBDA_RUNTIME_API.
+func NewServer() (*Server, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("could not start runtime API server: %s", err)
+	}
+
+	return &Server{
+		listener: listener,
+		closed:   make(chan struct{}),
+		next:     make(chan pendingInvocation),
+		pending:  map[string]chan Result{},
+	}, nil
+}
+
+// Addr returns the host:port that the container should be told is
+// AWS_LAMBDA_RUNTIME_API.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Serve blocks, handling Runtime API and Extensions API requests until the
+// listener is closed (by Shutdown). Run it in its own goroutine.
+func (s *Server) Serve() error {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/2018-06-01/runtime/invocation/next", s.handleInvocationNext)
+	mux.HandleFunc("/2018-06-01/runtime/invocation/", s.handleInvocationResult)
+	mux.HandleFunc("/2018-06-01/runtime/init/error", s.handleInitError)
+	mux.HandleFunc("/2020-01-01/extension/register", s.handleExtensionRegister)
+	mux.HandleFunc("/2020-01-01/extension/event/next", s.handleExtensionEventNext)
+
+	return http.Serve(s.listener, mux)
+}
+
+// Invoke delivers payload as the next invocation and blocks until the
+// runtime calls back with a response or an error, or the server is shut
+// down first - whichever container thread is waiting on the other end of
+// next/pending is released by the close(s.closed) in Shutdown, so neither
+// side of an invocation that never completes leaks a goroutine.
+func (s *Server) Invoke(payload string) Result {
+	id := uuid.New().String()
+
+	resultCh := make(chan Result, 1)
+	s.mu.Lock()
+	s.pending[id] = resultCh
+	s.mu.Unlock()
+
+	select {
+	case s.next <- pendingInvocation{id: id, payload: payload}:
+	case <-s.closed:
+		return Result{Error: fmt.Errorf("runtime API server shut down before the container requested an invocation")}
+	}
+
+	select {
+	case result := <-resultCh:
+		return result
+	case <-s.closed:
+		return Result{Error: fmt.Errorf("runtime API server shut down before the container responded")}
+	}
+}
+
+// Shutdown sends a SHUTDOWN event to every registered extension, gives
+// them up to deadline to react, then closes the listener and unblocks any
+// in-flight Invoke/handler call. The deadline wait is skipped entirely when
+// no extension ever registered, so a plain function that never touches the
+// Extensions API doesn't pay extensionShutdownDeadline's latency on every
+// single invocation.
+func (s *Server) Shutdown(deadline time.Duration) {
+	s.mu.Lock()
+	exts := s.extensions
+	s.mu.Unlock()
+
+	for _, ext := range exts {
+		select {
+		case ext.events <- "SHUTDOWN":
+		default:
+		}
+	}
+
+	if len(exts) > 0 {
+		time.Sleep(deadline)
+	}
+	s.closeOnce.Do(func() { close(s.closed) })
+	s.listener.Close()
+}
+
+func (s *Server) handleInvocationNext(w http.ResponseWriter, r *http.Request) {
+	select {
+	case inv := <-s.next:
+		w.Header().Set("Lambda-Runtime-Aws-Request-Id", inv.id)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(inv.payload))
+	case <-s.closed:
+		http.Error(w, "runtime API server shutting down", http.StatusServiceUnavailable)
+	}
+}
+
+func (s *Server) handleInvocationResult(w http.ResponseWriter, r *http.Request) {
+	// Path is one of:
+	//   /2018-06-01/runtime/invocation/{AwsRequestId}/response
+	//   /2018-06-01/runtime/invocation/{AwsRequestId}/error
+	path := strings.TrimPrefix(r.URL.Path, "/2018-06-01/runtime/invocation/")
+	parts := strings.SplitN(path, "/", 2)
+	if len(parts) != 2 {
+		http.NotFound(w, r)
+		return
+	}
+	id, action := parts[0], parts[1]
+
+	s.mu.Lock()
+	resultCh, ok := s.pending[id]
+	if ok {
+		delete(s.pending, id)
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		http.Error(w, fmt.Sprintf("unknown invocation id %s", id), http.StatusBadRequest)
+		return
+	}
+
+	body := readBody(r)
+
+	switch action {
+	case "response":
+		resultCh <- Result{Payload: body}
+	case "error":
+		resultCh <- Result{Error: fmt.Errorf("%s", body)}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleInitError(w http.ResponseWriter, r *http.Request) {
+	// The real service records this against the function's init, but with
+	// no invocation outstanding yet there's nothing to unblock - the
+	// request is acknowledged and its body is not read or recorded.
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (s *Server) handleExtensionRegister(w http.ResponseWriter, r *http.Request) {
+	ext := &extension{
+		id:     uuid.New().String(),
+		events: make(chan string, 1),
+	}
+
+	s.mu.Lock()
+	s.extensions = append(s.extensions, ext)
+	s.mu.Unlock()
+
+	w.Header().Set("Lambda-Extension-Identifier", ext.id)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string][]string{"functions": {"*"}})
+}
+
+func (s *Server) handleExtensionEventNext(w http.ResponseWriter, r *http.Request) {
+	id := r.Header.Get("Lambda-Extension-Identifier")
+
+	s.mu.Lock()
+	var ext *extension
+	for _, e := range s.extensions {
+		if e.id == id {
+			ext = e
+		}
+	}
+	s.mu.Unlock()
+
+	if ext == nil {
+		http.Error(w, "unregistered extension", http.StatusForbidden)
+		return
+	}
+
+	select {
+	case event := <-ext.events:
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"eventType": event})
+	case <-s.closed:
+		http.Error(w, "runtime API server shutting down", http.StatusServiceUnavailable)
+	}
+}
+
+func readBody(r *http.Request) string {
+	defer r.Body.Close()
+	data, _ := ioutil.ReadAll(r.Body)
+	return string(data)
+}
@@ -0,0 +1,46 @@
+package runtimeapi
+
+import (
+	"testing"
+	"time"
+)
+
+func TestShutdownSkipsDeadlineWithNoExtensions(t *testing.T) {
+	s, err := NewServer()
+	if err != nil {
+		t.Fatalf("could not start server: %s", err)
+	}
+	go s.Serve()
+
+	start := time.Now()
+	s.Shutdown(time.Second)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Fatalf("Shutdown took %s with no registered extensions, want it to skip the deadline wait", elapsed)
+	}
+}
+
+func TestShutdownUnblocksInFlightInvoke(t *testing.T) {
+	s, err := NewServer()
+	if err != nil {
+		t.Fatalf("could not start server: %s", err)
+	}
+	go s.Serve()
+
+	resultCh := make(chan Result, 1)
+	go func() { resultCh <- s.Invoke(`{}`) }()
+
+	// Give Invoke a moment to block on s.next before shutting down, so this
+	// actually exercises the closed-channel unblock path rather than racing
+	// ahead of it.
+	time.Sleep(10 * time.Millisecond)
+	s.Shutdown(0)
+
+	select {
+	case result := <-resultCh:
+		if result.Error == nil {
+			t.Fatal("expected Invoke to return an error once the server shut down before any response")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Invoke did not unblock after Shutdown - goroutine leak")
+	}
+}
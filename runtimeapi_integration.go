@@ -0,0 +1,36 @@
+package main
+
+import (
+	"time"
+
+	"github.com/awslabs/aws-sam-local/runtimeapi"
+	"github.com/sirupsen/logrus"
+)
+
+// startRuntimeAPI starts an in-process Runtime API + Extensions API server
+// for a single container invocation, injecting its address into the
+// container's environment as AWS_LAMBDA_RUNTIME_API so that provided/
+// provided.al2 runtimes and the aws-lambda-go client work unmodified. The
+// returned server's Invoke method is how the caller actually hands the
+// event to a container that speaks the Runtime API rather than stdin; the
+// shutdown func sends SHUTDOWN to any registered extensions, honoring
+// extensionShutdownDeadline before the caller kills the container.
+func startRuntimeAPI(envVarsOverrides map[string]string, extensionShutdownDeadline time.Duration, log *logrus.Entry) (server *runtimeapi.Server, shutdown func()) {
+
+	server, err := runtimeapi.NewServer()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		if err := server.Serve(); err != nil {
+			log.WithError(err).Debug("Runtime API server stopped")
+		}
+	}()
+
+	envVarsOverrides["AWS_LAMBDA_RUNTIME_API"] = server.Addr()
+
+	return server, func() {
+		server.Shutdown(extensionShutdownDeadline)
+	}
+}
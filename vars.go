@@ -0,0 +1,70 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// parseVarFile reads a YAML or JSON file of CloudFormation parameter
+// overrides (a flat map of parameter name to value) from disk.
+func parseVarFile(path string) (map[string]string, error) {
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read var file %s: %s", path, err)
+	}
+
+	vars := map[string]string{}
+	if err := yaml.Unmarshal(data, &vars); err != nil {
+		return nil, fmt.Errorf("var file %s must be valid YAML or JSON: %s", path, err)
+	}
+
+	return vars, nil
+}
+
+// parseVarFlags parses a list of repeatable `--var name=value` flags into a
+// map, in the order they were provided.
+func parseVarFlags(flags []string) (map[string]string, error) {
+
+	vars := map[string]string{}
+	for _, flag := range flags {
+		parts := strings.SplitN(flag, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("--var must be in the form name=value, got '%s'", flag)
+		}
+		vars[parts[0]] = parts[1]
+	}
+
+	return vars, nil
+}
+
+// resolveParameterOverrides merges a `--var-file` (applied first) with
+// repeatable `--var name=value` flags (applied last, so they win on
+// conflict), producing the final set of CloudFormation parameter overrides.
+func resolveParameterOverrides(varFile string, varFlags []string) (map[string]string, error) {
+
+	overrides := map[string]string{}
+
+	if varFile != "" {
+		fileVars, err := parseVarFile(varFile)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range fileVars {
+			overrides[name] = value
+		}
+	}
+
+	flagVars, err := parseVarFlags(varFlags)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range flagVars {
+		overrides[name] = value
+	}
+
+	return overrides, nil
+}
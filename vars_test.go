@@ -0,0 +1,25 @@
+package main
+
+import "testing"
+
+func TestResolveParameterOverridesFlagsWinOverVarFile(t *testing.T) {
+	varFile := writeTempTemplate(t, "Env: dev\nRegion: us-east-1\n")
+
+	overrides, err := resolveParameterOverrides(varFile, []string{"Env=prod"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if overrides["Env"] != "prod" {
+		t.Fatalf("expected --var to win over the var file, got %q", overrides["Env"])
+	}
+	if overrides["Region"] != "us-east-1" {
+		t.Fatalf("expected the var file's Region to survive untouched, got %q", overrides["Region"])
+	}
+}
+
+func TestParseVarFlagsRejectsMissingEquals(t *testing.T) {
+	if _, err := parseVarFlags([]string{"NoEqualsSign"}); err == nil {
+		t.Fatal("expected an error for a --var flag with no '='")
+	}
+}